@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local image cache",
+	Long:  `cache lets you inspect and repair minikube's content-addressed image cache under $MINIKUBE_HOME/cache/images.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists every image currently stored in the cache",
+	Run:   runCacheList,
+}
+
+var cacheDeleteCmd = &cobra.Command{
+	Use:   "delete [image]",
+	Short: "Deletes an image from the cache",
+	Run:   runCacheDelete,
+}
+
+var cacheReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Pulls every image for the current bootstrapper and kubernetes version that is missing from the cache or fails its digest check",
+	Run:   runCacheReload,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheDeleteCmd)
+	cacheCmd.AddCommand(cacheReloadCmd)
+	RootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheList(cmd *cobra.Command, args []string) {
+	images, err := machine.ListCachedImages()
+	if err != nil {
+		glog.Exitf("Error listing cached images: %v", err)
+	}
+	for _, image := range images {
+		fmt.Println(image)
+	}
+}
+
+func runCacheDelete(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: minikube cache delete [image]")
+		os.Exit(1)
+	}
+	if err := machine.DeleteCachedImage(args[0]); err != nil {
+		glog.Exitf("Error deleting %s from cache: %v", args[0], err)
+	}
+}
+
+func runCacheReload(cmd *cobra.Command, args []string) {
+	k8sVersion := viper.GetString(kubernetesVersion)
+	clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
+	imageRepo := viper.GetString(imageRepository)
+
+	if err := machine.CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper, imageRepo); err != nil {
+		glog.Exitf("Error reloading image cache: %v", err)
+	}
+	fmt.Println("Image cache reloaded.")
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Add, remove, or list additional cluster nodes",
+}
+
+var nodeAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds a node to the given cluster",
+	Run:   runNodeAdd,
+}
+
+var nodeDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Deletes a node from a cluster",
+	Run:   runNodeDelete,
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the nodes in a cluster",
+	Run:   runNodeList,
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeAddCmd)
+	nodeCmd.AddCommand(nodeDeleteCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	RootCmd.AddCommand(nodeCmd)
+}
+
+func runNodeAdd(cmd *cobra.Command, args []string) {
+	config, err := cfg.Load()
+	if err != nil {
+		glog.Exitf("Error loading profile config: %v", err)
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Exitf("Error getting client: %v", err)
+	}
+	defer api.Close()
+
+	clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
+	k8sBootstrapper, err := GetClusterBootstrapper(api, clusterBootstrapper)
+	if err != nil {
+		glog.Exitf("Error getting cluster bootstrapper: %v", err)
+	}
+
+	endpoint := config.KubernetesConfig.NodeIP
+	newNodes, err := provisionAdditionalNodes(api, k8sBootstrapper, config.MachineConfig, config.KubernetesConfig, endpoint, len(config.Nodes), 1)
+	if err != nil {
+		glog.Errorln("Error adding node: ", err)
+		cmdutil.MaybeReportErrorAndExit(err)
+	}
+
+	config.Nodes = append(config.Nodes, newNodes...)
+	if err := saveConfig(*config); err != nil {
+		glog.Errorln("Error saving profile cluster configuration: ", err)
+	}
+	fmt.Println("Node successfully added.")
+}
+
+func runNodeDelete(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: minikube node delete [name]")
+		os.Exit(1)
+	}
+	name := args[0]
+
+	config, err := cfg.Load()
+	if err != nil {
+		glog.Exitf("Error loading profile config: %v", err)
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Exitf("Error getting client: %v", err)
+	}
+	defer api.Close()
+
+	if err := cluster.DeleteHost(api, name); err != nil {
+		glog.Errorln("Error deleting node: ", err)
+		cmdutil.MaybeReportErrorAndExit(err)
+	}
+
+	remaining := config.Nodes[:0]
+	for _, node := range config.Nodes {
+		if node.Name != name {
+			remaining = append(remaining, node)
+		}
+	}
+	config.Nodes = remaining
+	if err := saveConfig(*config); err != nil {
+		glog.Errorln("Error saving profile cluster configuration: ", err)
+	}
+	fmt.Printf("Node %s successfully deleted.\n", name)
+}
+
+func runNodeList(cmd *cobra.Command, args []string) {
+	config, err := cfg.Load()
+	if err != nil {
+		glog.Exitf("Error loading profile config: %v", err)
+	}
+	for _, node := range config.Nodes {
+		fmt.Println(node.Name)
+	}
+}
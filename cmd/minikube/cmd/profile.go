@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile [MINIKUBE_PROFILE_NAME]",
+	Short: "Switches the active minikube profile",
+	Long: `profile sets the active minikube profile, so that subsequent
+minikube commands (without -p) act on it. Every profile has its own
+machine and its own context in the shared kubeconfig, and keeps its
+cluster config and certs under $MINIKUBE_HOME/profiles/<name>, so
+switching profiles never mixes state between clusters.`,
+	Run: runProfile,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all minikube profiles",
+	Run:   runProfileList,
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	RootCmd.AddCommand(profileCmd)
+}
+
+func runProfile(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		fmt.Println(viper.GetString(cfg.MachineProfile))
+		return
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: minikube profile [MINIKUBE_PROFILE_NAME]")
+		os.Exit(1)
+	}
+	profile := args[0]
+
+	if _, err := os.Stat(constants.GetProfileFile(profile)); os.IsNotExist(err) {
+		glog.Exitf("profile %q was not found, run `minikube start -p %s` first", profile, profile)
+	}
+
+	viper.Set(cfg.MachineProfile, profile)
+	if err := viper.WriteConfig(); err != nil {
+		glog.Exitf("Error saving active profile: %v", err)
+	}
+	fmt.Printf("minikube profile was successfully set to %s\n", profile)
+}
+
+func runProfileList(cmd *cobra.Command, args []string) {
+	entries, err := ioutil.ReadDir(constants.GetProfilesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("no profiles have been created yet")
+			return
+		}
+		glog.Exitf("Error listing profiles: %v", err)
+	}
+
+	active := viper.GetString(cfg.MachineProfile)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		marker := " "
+		if entry.Name() == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, entry.Name())
+	}
+}
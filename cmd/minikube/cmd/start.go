@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
@@ -37,11 +38,14 @@ import (
 	"golang.org/x/sync/errgroup"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/addons"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
 	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/atomicfile"
 	"k8s.io/minikube/pkg/util/kubeconfig"
 	"k8s.io/minikube/pkg/version"
 )
@@ -78,6 +82,9 @@ const (
 	vsockPorts            = "hyperkit-vsock-ports"
 	gpu                   = "gpu"
 	embedCerts            = "embed-certs"
+	nodes                 = "nodes"
+	addonList             = "addons"
+	imageRepository       = "image-repository"
 )
 
 var (
@@ -90,6 +97,30 @@ var (
 	extraOptions     pkgutil.ExtraOptionSlice
 )
 
+// addonsToEnable returns the set of addons that should be applied this run:
+// whatever was passed via --addons, plus whatever was already enabled on
+// this profile, so that a bare `minikube start` on an existing cluster
+// keeps the addons the user turned on before.
+func addonsToEnable(oldConfig *cfg.Config) []string {
+	seen := map[string]bool{}
+	var result []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	for _, name := range viper.GetStringSlice(addonList) {
+		add(name)
+	}
+	if oldConfig != nil {
+		for _, name := range oldConfig.Addons {
+			add(name)
+		}
+	}
+	return result
+}
+
 // startCmd represents the start command
 var startCmd = &cobra.Command{
 	Use:   "start",
@@ -120,14 +151,16 @@ func runStart(cmd *cobra.Command, args []string) {
 		glog.Infoln("Viper configuration:")
 		viper.Debug()
 	}
+	profile := viper.GetString(cfg.MachineProfile)
 	shouldCacheImages := viper.GetBool(cacheImages)
 	k8sVersion := viper.GetString(kubernetesVersion)
 	clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
+	imageRepo := viper.GetString(imageRepository)
 
 	var groupCacheImages errgroup.Group
 	if shouldCacheImages {
 		groupCacheImages.Go(func() error {
-			return machine.CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper)
+			return machine.CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper, imageRepo)
 		})
 	}
 
@@ -269,6 +302,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		CRISocket:              viper.GetString(criSocket),
 		NetworkPlugin:          viper.GetString(networkPlugin),
 		ServiceCIDR:            viper.GetString(serviceCIDR),
+		ImageRepository:        imageRepo,
 		ExtraOptions:           extraOptions,
 		ShouldLoadCachedImages: shouldCacheImages,
 		EnableDefaultCNI:       viper.GetBool(enableDefaultCNI),
@@ -325,9 +359,9 @@ func runStart(cmd *cobra.Command, args []string) {
 	kubeCfgSetup := &kubeconfig.KubeConfigSetup{
 		ClusterName:          cfg.GetMachineName(),
 		ClusterServerAddress: kubeHost,
-		ClientCertificate:    constants.MakeMiniPath("client.crt"),
-		ClientKey:            constants.MakeMiniPath("client.key"),
-		CertificateAuthority: constants.MakeMiniPath("ca.crt"),
+		ClientCertificate:    constants.ProfilePath(profile, "client.crt"),
+		ClientKey:            constants.ProfilePath(profile, "client.key"),
+		CertificateAuthority: constants.ProfilePath(profile, "ca.crt"),
 		KeepContext:          viper.GetBool(keepContext),
 		EmbedCerts:           viper.GetBool(embedCerts),
 	}
@@ -397,6 +431,22 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	enabledAddons := addonsToEnable(oldConfig)
+	if len(enabledAddons) > 0 {
+		fmt.Printf("Enabling addons: %s\n", strings.Join(enabledAddons, ", "))
+		if err := addons.EnableAll(k8sBootstrapper, enabledAddons); err != nil {
+			glog.Errorln("Error enabling addons: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+	}
+	clusterConfig.Addons = enabledAddons
+	if oldConfig != nil {
+		clusterConfig.Nodes = oldConfig.Nodes
+	}
+	if err := saveConfig(clusterConfig); err != nil {
+		glog.Errorln("Error saving profile cluster configuration: ", err)
+	}
+
 	// Block until the cluster is healthy.
 	fmt.Print("Verifying kubelet health ...")
 	kStat := func() (err error) {
@@ -429,6 +479,19 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 	fmt.Println()
 
+	if toAdd := viper.GetInt(nodes) - 1 - len(clusterConfig.Nodes); toAdd > 0 {
+		fmt.Printf("Provisioning %d additional node(s)...\n", toAdd)
+		newNodes, err := provisionAdditionalNodes(api, k8sBootstrapper, config, kubernetesConfig, kubeHost, len(clusterConfig.Nodes), toAdd)
+		if err != nil {
+			glog.Errorln("Error provisioning additional nodes: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		clusterConfig.Nodes = append(clusterConfig.Nodes, newNodes...)
+		if err := saveConfig(clusterConfig); err != nil {
+			glog.Errorln("Error saving profile cluster configuration: ", err)
+		}
+	}
+
 	// start 9p server mount
 	if viper.GetBool(createMount) {
 		fmt.Printf("Setting up hostmount on %s...\n", viper.GetString(mountString))
@@ -449,7 +512,7 @@ func runStart(cmd *cobra.Command, args []string) {
 			glog.Errorf("Error running command minikube mount %v", err)
 			cmdutil.MaybeReportErrorAndExit(err)
 		}
-		err = ioutil.WriteFile(filepath.Join(constants.GetMinipath(), constants.MountProcessFileName), []byte(strconv.Itoa(mountCmd.Process.Pid)), 0644)
+		err = ioutil.WriteFile(constants.ProfilePath(profile, constants.MountProcessFileName), []byte(strconv.Itoa(mountCmd.Process.Pid)), 0644)
 		if err != nil {
 			glog.Errorf("Error writing mount process pid to file: %v", err)
 			cmdutil.MaybeReportErrorAndExit(err)
@@ -541,10 +604,53 @@ func init() {
 	startCmd.Flags().String(vpnkitSock, "", "Location of the VPNKit socket used for networking. If empty, disables Hyperkit VPNKitSock, if 'auto' uses Docker for Mac VPNKit connection, otherwise uses the specified VSock.")
 	startCmd.Flags().StringSlice(vsockPorts, []string{}, "List of guest VSock ports that should be exposed as sockets on the host (Only supported on with hyperkit now).")
 	startCmd.Flags().Bool(gpu, false, "Enable experimental NVIDIA GPU support in minikube (works only with kvm2 driver on Linux)")
+	startCmd.Flags().Int(nodes, 1, "The total number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().StringSlice(addonList, nil, fmt.Sprintf("Enable addons at cluster creation. Valid addons: %v", addons.Names()))
+	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to k8s.gcr.io. The value is used as given; minikube does not pick a mirror for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	viper.BindPFlags(startCmd.Flags())
 	RootCmd.AddCommand(startCmd)
 }
 
+// provisionAdditionalNodes joins count worker nodes to the already-running
+// control plane, in parallel. Each node is provisioned exactly the way the
+// control plane node was, minus the bootstrapping steps that only make
+// sense once per cluster (certs, kubeconfig). startIndex is the number of
+// additional nodes already joined to this profile, so that a later `minikube
+// node add` continues the naming sequence instead of colliding with nodes
+// an earlier call already created.
+func provisionAdditionalNodes(api libmachine.API, k8sBootstrapper bootstrapper.Bootstrapper, machineConfig cfg.MachineConfig, kubernetesConfig cfg.KubernetesConfig, controlPlaneEndpoint string, startIndex, count int) ([]cfg.Node, error) {
+	token, caHash, err := k8sBootstrapper.GenerateJoinArgs()
+	if err != nil {
+		return nil, fmt.Errorf("generating join token: %v", err)
+	}
+
+	nodes := make([]cfg.Node, count)
+	var g errgroup.Group
+	for i := 1; i <= count; i++ {
+		i := i
+		nodeName := fmt.Sprintf("%s-m%02d", cfg.GetMachineName(), startIndex+i+1)
+		g.Go(func() error {
+			nodeHost, err := cluster.StartHost(api, machineConfig)
+			if err != nil {
+				return fmt.Errorf("starting node %s: %v", nodeName, err)
+			}
+			if err := k8sBootstrapper.JoinNode(nodeHost, token, caHash, controlPlaneEndpoint); err != nil {
+				return fmt.Errorf("joining node %s: %v", nodeName, err)
+			}
+			nodeIP, err := nodeHost.Driver.GetIP()
+			if err != nil {
+				return fmt.Errorf("getting IP for node %s: %v", nodeName, err)
+			}
+			nodes[i-1] = cfg.Node{Name: nodeName, IP: nodeIP}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
 // saveConfig saves profile cluster configuration in
 // $MINIKUBE_HOME/profiles/<profilename>/config.json
 func saveConfig(clusterConfig cfg.Config) error {
@@ -567,30 +673,5 @@ func saveConfig(clusterConfig cfg.Config) error {
 }
 
 func saveConfigToFile(data []byte, file string) error {
-	if _, err := os.Stat(file); os.IsNotExist(err) {
-		return ioutil.WriteFile(file, data, 0600)
-	}
-
-	tmpfi, err := ioutil.TempFile(filepath.Dir(file), "config.json.tmp")
-	if err != nil {
-		return err
-	}
-	defer os.Remove(tmpfi.Name())
-
-	if err = ioutil.WriteFile(tmpfi.Name(), data, 0600); err != nil {
-		return err
-	}
-
-	if err = tmpfi.Close(); err != nil {
-		return err
-	}
-
-	if err = os.Remove(file); err != nil {
-		return err
-	}
-
-	if err = os.Rename(tmpfi.Name(), file); err != nil {
-		return err
-	}
-	return nil
+	return atomicfile.WriteFile(file, data, 0600)
 }
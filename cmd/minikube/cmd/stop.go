@@ -0,0 +1,86 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stops a running local kubernetes cluster",
+	Long:  `Stops a local kubernetes cluster running in Virtualbox. This command stops the VM itself, leaving all files intact.`,
+	Run:   runStop,
+}
+
+func init() {
+	RootCmd.AddCommand(stopCmd)
+}
+
+func runStop(cmd *cobra.Command, args []string) {
+	profile := viper.GetString(cfg.MachineProfile)
+	config, configErr := cfg.Load()
+
+	if configErr == nil {
+		if nodeIP := net.ParseIP(config.KubernetesConfig.NodeIP); nodeIP != nil {
+			if err := tunnel.CleanupProfile(profile, nodeIP); err != nil {
+				glog.Errorf("Error cleaning up tunnel routes: %v", err)
+			}
+		}
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Exitf("Error getting client: %v", err)
+	}
+	defer api.Close()
+
+	if configErr == nil {
+		for _, node := range config.Nodes {
+			if err := stopHost(api, node.Name); err != nil {
+				glog.Errorf("Error stopping node %s: %v", node.Name, err)
+			}
+		}
+	}
+
+	if err := cluster.StopHost(api); err != nil {
+		glog.Errorln("Error stopping machine: ", err)
+		cmdutil.MaybeReportErrorAndExit(err)
+	}
+	fmt.Println("Machine stopped.")
+}
+
+// stopHost stops the named node's VM, which cluster.StopHost doesn't do:
+// that one only knows about the profile's default/control-plane machine.
+func stopHost(api libmachine.API, name string) error {
+	h, err := api.Load(name)
+	if err != nil {
+		return fmt.Errorf("loading %s: %v", name, err)
+	}
+	return h.Stop()
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+	"k8s.io/minikube/pkg/util/kubeconfig"
+)
+
+const tunnelCIDR = "tunnel-cidr"
+
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel",
+	Short: "Connect to LoadBalancer services",
+	Long: `tunnel runs as a daemon process and gives LoadBalancer type Services a
+real, routable IP address on the host, so that "kubectl get svc" shows an
+external IP instead of staying Pending forever. Run it in a separate
+terminal and leave it running for as long as you need LoadBalancer access;
+Ctrl-C cleans up the routes it installed.`,
+	Run: runTunnel,
+}
+
+func init() {
+	// Distinct from both the default host-only CIDR (192.168.99.1/24) and
+	// the default service CIDR, so a default `minikube tunnel` works
+	// out of the box against a default-configured cluster.
+	tunnelCmd.Flags().String(tunnelCIDR, "192.168.59.100/24", "The CIDR tunnel allocates LoadBalancer ingress IPs from. Must not overlap the service or host-only CIDR.")
+	viper.BindPFlags(tunnelCmd.Flags())
+	RootCmd.AddCommand(tunnelCmd)
+}
+
+func runTunnel(cmd *cobra.Command, args []string) {
+	profile := viper.GetString(cfg.MachineProfile)
+
+	config, err := cfg.Load()
+	if err != nil {
+		glog.Exitf("Error loading profile config: %v", err)
+	}
+
+	client, err := kubeconfig.Client(profile)
+	if err != nil {
+		glog.Exitf("Error getting kubernetes client: %v", err)
+	}
+
+	nodeIP := net.ParseIP(config.KubernetesConfig.NodeIP)
+	if nodeIP == nil {
+		glog.Exitf("Invalid node IP %q in profile config", config.KubernetesConfig.NodeIP)
+	}
+
+	manager, err := tunnel.NewManager(client, tunnel.Config{
+		Profile:      profile,
+		NodeIP:       nodeIP,
+		ServiceCIDR:  config.KubernetesConfig.ServiceCIDR,
+		HostOnlyCIDR: config.MachineConfig.HostOnlyCIDR,
+		CIDR:         viper.GetString(tunnelCIDR),
+	})
+	if err != nil {
+		glog.Exitf("Error creating tunnel: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		fmt.Println("\nStopping tunnel and cleaning up routes...")
+		close(stopCh)
+	}()
+
+	fmt.Printf("Starting tunnel for profile %s, pointing routes at %s...\n", profile, nodeIP)
+	if err := manager.Run(stopCh); err != nil {
+		glog.Exitf("Error running tunnel: %v", err)
+	}
+}
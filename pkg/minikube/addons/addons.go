@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package addons implements minikube's pluggable cluster addons: small
+// bundles of manifests (and, where needed, host-side setup) that can be
+// turned on with `minikube start --addons=name` or `minikube addons enable
+// name` without the user having to know where the manifests live.
+package addons
+
+import (
+	"fmt"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+// Addon is a self-contained cluster feature: the manifests it ships, and
+// how to turn it on or off against a running cluster.
+type Addon struct {
+	Name    string
+	Assets  []*assets.MemoryAsset
+	Enable  func(bootstrapper.Bootstrapper) error
+	Disable func(bootstrapper.Bootstrapper) error
+}
+
+// registry holds every addon minikube ships out of the box, keyed by name.
+var registry = map[string]*Addon{}
+
+func register(a *Addon) {
+	registry[a.Name] = a
+}
+
+// Get looks up a built-in addon by name.
+func Get(name string) (*Addon, error) {
+	a, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a valid addon", name)
+	}
+	return a, nil
+}
+
+// Names returns every addon minikube knows how to enable.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// EnableAll turns on every named addon against the given bootstrapper,
+// applying each addon's manifests via ApplyManifests. It stops at the
+// first failure so that a partially-applied addon doesn't mask the error.
+func EnableAll(bs bootstrapper.Bootstrapper, names []string) error {
+	for _, name := range names {
+		addon, err := Get(name)
+		if err != nil {
+			return err
+		}
+		if err := addon.Enable(bs); err != nil {
+			return fmt.Errorf("enabling addon %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// addonCopyableFiles widens a slice of concrete MemoryAssets to the
+// CopyableFile interface the bootstrapper deals in.
+func addonCopyableFiles(addonAssets []*assets.MemoryAsset) []assets.CopyableFile {
+	a := make([]assets.CopyableFile, 0, len(addonAssets))
+	for _, asset := range addonAssets {
+		a = append(a, asset)
+	}
+	return a
+}
+
+// applyAssets is the Enable implementation shared by every built-in addon:
+// apply its manifests as-is via the bootstrapper.
+func applyAssets(bs bootstrapper.Bootstrapper, addonAssets []*assets.MemoryAsset) error {
+	return bs.ApplyManifests(addonCopyableFiles(addonAssets))
+}
+
+// newAddon builds the Addon every built-in addon in this package needs:
+// Enable applies manifestAssets as-is, Disable removes exactly the same
+// set. Built-ins that need anything more than that (host-side setup,
+// version gating) define Enable/Disable themselves instead of using this.
+func newAddon(name string, manifestAssets []*assets.MemoryAsset) *Addon {
+	return &Addon{
+		Name:   name,
+		Assets: manifestAssets,
+		Enable: func(bs bootstrapper.Bootstrapper) error {
+			return applyAssets(bs, manifestAssets)
+		},
+		Disable: func(bs bootstrapper.Bootstrapper) error {
+			return bs.DeleteManifests(addonCopyableFiles(manifestAssets))
+		},
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+func init() {
+	register(newAddon("metrics-server", []*assets.MemoryAsset{
+		assets.NewMemoryAsset(metricsServerRBACYAML, "/etc/kubernetes/addons", "metrics-server-rbac.yaml", "0640"),
+		assets.NewMemoryAsset(metricsServerYAML, "/etc/kubernetes/addons", "metrics-server.yaml", "0640"),
+		assets.NewMemoryAsset(metricsServerAPIServiceYAML, "/etc/kubernetes/addons", "metrics-server-apiservice.yaml", "0640"),
+	}))
+}
+
+// metricsServerRBACYAML lets metrics-server read node/pod stats from the
+// kubelet summary API and write into the metrics.k8s.io aggregated API, via
+// the metrics-server:system:auth-delegator and metrics-server-auth-reader
+// bindings the upstream chart uses.
+const metricsServerRBACYAML = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: metrics-server
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:metrics-server
+rules:
+- apiGroups: [""]
+  resources: ["pods", "nodes", "nodes/stats", "namespaces"]
+  verbs: ["get", "list", "watch"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: system:metrics-server
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:metrics-server
+subjects:
+- kind: ServiceAccount
+  name: metrics-server
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: metrics-server:system:auth-delegator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
+- kind: ServiceAccount
+  name: metrics-server
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: metrics-server-auth-reader
+  namespace: kube-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: extension-apiserver-authentication-reader
+subjects:
+- kind: ServiceAccount
+  name: metrics-server
+  namespace: kube-system
+`
+
+const metricsServerYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: metrics-server
+  namespace: kube-system
+  labels:
+    app: metrics-server
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: metrics-server
+  template:
+    metadata:
+      labels:
+        app: metrics-server
+    spec:
+      serviceAccountName: metrics-server
+      containers:
+      - name: metrics-server
+        image: k8s.gcr.io/metrics-server-amd64:v0.3.1
+        args:
+        - --kubelet-insecure-tls
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: metrics-server
+  namespace: kube-system
+  labels:
+    kubernetes.io/name: Metrics-server
+spec:
+  ports:
+  - port: 443
+    targetPort: 443
+  selector:
+    app: metrics-server
+`
+
+// metricsServerAPIServiceYAML registers metrics-server with the aggregation
+// layer. Without this, the Deployment comes up healthy but `kubectl top`
+// still fails: the apiserver has nothing routing metrics.k8s.io requests to
+// the metrics-server Service.
+const metricsServerAPIServiceYAML = `
+apiVersion: apiregistration.k8s.io/v1beta1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+spec:
+  service:
+    name: metrics-server
+    namespace: kube-system
+  group: metrics.k8s.io
+  version: v1beta1
+  insecureSkipTLSVerify: true
+  groupPriorityMinimum: 100
+  versionPriority: 100
+`
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+func init() {
+	register(newAddon("storage-provisioner", []*assets.MemoryAsset{
+		assets.NewMemoryAsset(storageProvisionerRBACYAML, "/etc/kubernetes/addons", "storage-provisioner-rbac.yaml", "0640"),
+		assets.NewMemoryAsset(storageProvisionerYAML, "/etc/kubernetes/addons", "storage-provisioner.yaml", "0640"),
+	}))
+}
+
+// storageProvisionerRBACYAML grants the pod's ServiceAccount the
+// PersistentVolume/PersistentVolumeClaim/StorageClass access its
+// provisioning loop needs. Without this the pod either fails admission (no
+// such ServiceAccount) or runs with no permission to create PVs.
+const storageProvisionerRBACYAML = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: storage-provisioner
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:storage-provisioner
+rules:
+- apiGroups: [""]
+  resources: ["persistentvolumes"]
+  verbs: ["get", "list", "watch", "create", "delete"]
+- apiGroups: [""]
+  resources: ["persistentvolumeclaims"]
+  verbs: ["get", "list", "watch", "update"]
+- apiGroups: ["storage.k8s.io"]
+  resources: ["storageclasses"]
+  verbs: ["get", "list", "watch"]
+- apiGroups: [""]
+  resources: ["events"]
+  verbs: ["create", "patch", "update"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: system:storage-provisioner
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:storage-provisioner
+subjects:
+- kind: ServiceAccount
+  name: storage-provisioner
+  namespace: kube-system
+`
+
+// storageProvisionerYAML runs minikube's hostpath-backed PV provisioner: it
+// watches for unbound PersistentVolumeClaims and creates matching
+// PersistentVolumes backed by directories under /tmp/hostpath-provisioner
+// on the node.
+const storageProvisionerYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: storage-provisioner
+  namespace: kube-system
+  labels:
+    integration-test: storage-provisioner
+    addonmanager.kubernetes.io/mode: Reconcile
+spec:
+  serviceAccountName: storage-provisioner
+  hostNetwork: true
+  containers:
+  - name: storage-provisioner
+    image: gcr.io/k8s-minikube/storage-provisioner:v1.8.1
+    command: ["/storage-provisioner"]
+    volumeMounts:
+    - name: tmp
+      mountPath: /tmp
+  volumes:
+  - name: tmp
+    hostPath:
+      path: /tmp
+      type: Directory
+`
@@ -0,0 +1,61 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrapper defines what it takes to stand up and manage a
+// Kubernetes control plane inside the minikube VM. Different bootstrappers
+// (kubeadm today, localkube historically) implement the same interface so
+// the rest of minikube doesn't need to know which one is in use.
+package bootstrapper
+
+import (
+	"net"
+
+	"github.com/docker/machine/libmachine/host"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Bootstrapper contains all the methods needed to bootstrap a Kubernetes cluster.
+//
+// StartCluster, RestartCluster, and UpdateCluster must honor
+// config.KubernetesConfig.ImageRepository: when set, every control-plane
+// image reference the implementation renders (e.g. kubeadm's own
+// ClusterConfiguration.imageRepository) needs to use it instead of
+// k8s.gcr.io, the same rewrite machine.RewriteImageRepository applies to
+// the separate --cache-images pre-pull path.
+type Bootstrapper interface {
+	StartCluster(config.KubernetesConfig) error
+	RestartCluster(config.KubernetesConfig) error
+	UpdateCluster(config.KubernetesConfig) error
+	SetupCerts(config.KubernetesConfig) error
+	GetKubeletStatus() (string, error)
+	GetApiServerStatus(ip net.IP) (string, error)
+
+	// ApplyManifests and DeleteManifests apply and remove a set of
+	// manifests against the already-running cluster, used by the addons
+	// subsystem to turn built-in addons on and off.
+	ApplyManifests(files []assets.CopyableFile) error
+	DeleteManifests(files []assets.CopyableFile) error
+
+	// GenerateJoinArgs returns the bootstrap token and CA cert hash a new
+	// node needs in order to join the existing control plane.
+	GenerateJoinArgs() (token string, caHash string, err error)
+
+	// JoinNode joins the already-provisioned host to the cluster fronted
+	// by controlPlaneEndpoint, using the credentials GenerateJoinArgs
+	// returned.
+	JoinNode(h *host.Host, token, caHash, controlPlaneEndpoint string) error
+}
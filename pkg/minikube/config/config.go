@@ -0,0 +1,140 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config defines the shape of a minikube profile's persisted
+// cluster configuration ($MINIKUBE_HOME/profiles/<profile>/config.json)
+// and the handful of helpers every command uses to read the active
+// profile's name and config off of disk.
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+const (
+	// MachineProfile is the viper key holding the name of the profile
+	// (and underlying machine) that the current command targets.
+	MachineProfile = "profile"
+
+	// WantNoneDriverWarning is the viper key controlling whether to warn
+	// the user about the limitations of --vm-driver=none.
+	WantNoneDriverWarning = "WantNoneDriverWarning"
+)
+
+// Config is a minikube profile's full persisted cluster configuration.
+type Config struct {
+	MachineConfig    MachineConfig
+	KubernetesConfig KubernetesConfig
+
+	// Addons lists the addons currently enabled on this profile, so that
+	// a bare `minikube start` on an existing cluster can re-enable them.
+	Addons []string
+
+	// Nodes lists every node that has been joined to this profile's
+	// cluster beyond the initial control-plane node.
+	Nodes []Node
+}
+
+// MachineConfig contains the parameters used to start a minikube VM.
+type MachineConfig struct {
+	MinikubeISO         string
+	Memory              int
+	CPUs                int
+	DiskSize            int
+	VMDriver            string
+	ContainerRuntime    string
+	HyperkitVpnKitSock  string
+	HyperkitVSockPorts  []string
+	XhyveDiskDriver     string
+	NFSShare            []string
+	NFSSharesRoot       string
+	DockerEnv           []string
+	DockerOpt           []string
+	InsecureRegistry    []string
+	RegistryMirror      []string
+	HostOnlyCIDR        string
+	HypervVirtualSwitch string
+	KvmNetwork          string
+	Downloader          pkgutil.ISODownloader
+	DisableDriverMounts bool
+	UUID                string
+	GPU                 bool
+}
+
+// KubernetesConfig contains the parameters used to configure the Kubernetes
+// cluster running on top of the minikube VM.
+type KubernetesConfig struct {
+	KubernetesVersion string
+	NodeIP            string
+	NodePort          int
+	NodeName          string
+	APIServerName     string
+	APIServerNames    []string
+	APIServerIPs      []net.IP
+	DNSDomain         string
+	FeatureGates      string
+	ContainerRuntime  string
+	CRISocket         string
+	NetworkPlugin     string
+	ServiceCIDR       string
+	// ImageRepository, if set, replaces k8s.gcr.io in every control-plane
+	// image the bootstrapper pulls onto the VM (not just the separate
+	// --cache-images pre-pull path), for clusters where k8s.gcr.io isn't
+	// reachable.
+	ImageRepository        string
+	ExtraOptions           pkgutil.ExtraOptionSlice
+	ShouldLoadCachedImages bool
+	EnableDefaultCNI       bool
+}
+
+// Node is a machine that has been joined to a profile's cluster as a
+// worker, in addition to the profile's own control-plane node.
+type Node struct {
+	Name string
+	IP   string
+}
+
+// Load reads the active profile's persisted cluster configuration off of
+// disk. Callers that only care about a missing profile (e.g. the first
+// `minikube start`) can check os.IsNotExist on the returned error.
+func Load() (*Config, error) {
+	return loadConfigFromFile(viper.GetString(MachineProfile))
+}
+
+func loadConfigFromFile(profile string) (*Config, error) {
+	data, err := ioutil.ReadFile(constants.GetProfileFile(profile))
+	if err != nil {
+		return nil, err
+	}
+
+	var cc Config
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return nil, err
+	}
+	return &cc, nil
+}
+
+// GetMachineName returns the name of the machine backing the active
+// profile, which is simply the profile's name.
+func GetMachineName() string {
+	return viper.GetString(MachineProfile)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// minipath is $MINIKUBE_HOME/.minikube, the root every profile's state
+// lives under.
+func minipath() string {
+	home := os.Getenv("MINIKUBE_HOME")
+	if home == "" {
+		home, _ = os.UserHomeDir()
+	}
+	return filepath.Join(home, ".minikube")
+}
+
+// GetProfilesDir returns the directory holding every profile's state,
+// $MINIKUBE_HOME/.minikube/profiles.
+func GetProfilesDir() string {
+	return filepath.Join(minipath(), "profiles")
+}
+
+// ProfilePath joins path onto profile's state directory, e.g.
+// ProfilePath("minikube", "client.crt") is
+// $MINIKUBE_HOME/.minikube/profiles/minikube/client.crt.
+func ProfilePath(profile string, path ...string) string {
+	return filepath.Join(append([]string{GetProfilesDir(), profile}, path...)...)
+}
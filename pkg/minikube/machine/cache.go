@@ -0,0 +1,316 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// manifestMu serializes every load-modify-save of manifest.json.
+// CacheImagesForBootstrapper fans out one goroutine per image, and each one
+// reaches storeImage/deleteImage, so without a lock concurrent goroutines
+// race on the same file and clobber each other's entries.
+var manifestMu sync.Mutex
+
+// imageCacheDir is $MINIKUBE_HOME/cache/images, the root of the
+// content-addressed image store.
+func imageCacheDir() string {
+	return filepath.Join(constants.GetMinipath(), "cache", "images")
+}
+
+// manifestPath is the sidecar file mapping repo:tag -> sha256 digest, so a
+// cache lookup for an already-pulled tag is an O(1) manifest read instead
+// of a re-pull.
+func manifestPath() string {
+	return filepath.Join(imageCacheDir(), "manifest.json")
+}
+
+// imageManifest is the on-disk format of manifest.json.
+type imageManifest struct {
+	// Digests maps an image reference (e.g. "k8s.gcr.io/pause:3.1") to the
+	// sha256 digest of the tarball stored under sha256/<digest>.
+	Digests map[string]string `json:"digests"`
+}
+
+func loadManifest() (*imageManifest, error) {
+	m := &imageManifest{Digests: map[string]string{}}
+	data, err := ioutil.ReadFile(manifestPath())
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *imageManifest) save() error {
+	if err := os.MkdirAll(imageCacheDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(manifestPath(), data, 0644)
+}
+
+// digestPath is where the tarball for a given sha256 digest lives.
+func digestPath(digest string) string {
+	return filepath.Join(imageCacheDir(), "sha256", digest)
+}
+
+// cachedImagePath returns the path to image's tarball on disk if it has
+// already been cached and the digest on disk still matches the manifest,
+// detecting a corrupted or tampered cache entry.
+func cachedImagePath(image string) (string, bool) {
+	m, err := loadManifest()
+	if err != nil {
+		return "", false
+	}
+	digest, ok := m.Digests[image]
+	if !ok {
+		return "", false
+	}
+	path := digestPath(digest)
+	if actual, err := fileSHA256(path); err != nil || actual != digest {
+		return "", false
+	}
+	return path, true
+}
+
+// storeImage records tarballPath under the content-addressed store and
+// updates the manifest so future lookups for image are O(1).
+func storeImage(image, tarballPath string) error {
+	digest, err := fileSHA256(tarballPath)
+	if err != nil {
+		return fmt.Errorf("hashing %s: %v", tarballPath, err)
+	}
+
+	dest := digestPath(digest)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := copyFile(tarballPath, dest); err != nil {
+		return fmt.Errorf("storing %s: %v", image, err)
+	}
+
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	m, err := loadManifest()
+	if err != nil {
+		return err
+	}
+	m.Digests[image] = digest
+	return m.save()
+}
+
+// deleteImage removes image's manifest entry and, if no other reference
+// uses the same digest, its tarball.
+func deleteImage(image string) error {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	m, err := loadManifest()
+	if err != nil {
+		return err
+	}
+	digest, ok := m.Digests[image]
+	if !ok {
+		return nil
+	}
+	delete(m.Digests, image)
+
+	stillReferenced := false
+	for _, d := range m.Digests {
+		if d == digest {
+			stillReferenced = true
+			break
+		}
+	}
+	if !stillReferenced {
+		if err := os.Remove(digestPath(digest)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return m.save()
+}
+
+// listCachedImages returns every image reference currently in the
+// manifest, for `minikube cache list`.
+func listCachedImages() ([]string, error) {
+	m, err := loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	images := make([]string, 0, len(m.Digests))
+	for image := range m.Digests {
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// ListCachedImages returns every image reference currently in the cache,
+// for `minikube cache list`.
+func ListCachedImages() ([]string, error) {
+	return listCachedImages()
+}
+
+// DeleteCachedImage removes image from the cache, for `minikube cache
+// delete`.
+func DeleteCachedImage(image string) error {
+	return deleteImage(image)
+}
+
+// RewriteImageRepository rewrites the registry portion of a k8s.gcr.io
+// image reference to use repository instead, for deployments where
+// k8s.gcr.io isn't reachable (e.g. from mainland China). Images that
+// aren't under k8s.gcr.io are returned unchanged.
+func RewriteImageRepository(image, repository string) string {
+	if repository == "" {
+		return image
+	}
+	const upstream = "k8s.gcr.io/"
+	if !strings.HasPrefix(image, upstream) {
+		return image
+	}
+	return strings.TrimSuffix(repository, "/") + "/" + strings.TrimPrefix(image, upstream)
+}
+
+// CacheImagesForBootstrapper pulls every image clusterBootstrapper needs to
+// stand up k8sVersion and stores each one in the content-addressed cache,
+// rewriting each reference through imageRepository first (see
+// RewriteImageRepository) so that --image-repository actually changes what
+// gets pulled instead of only what gets loaded into the VM later.
+func CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper, imageRepository string) error {
+	images, err := imagesForBootstrapper(k8sVersion, clusterBootstrapper)
+	if err != nil {
+		return err
+	}
+
+	var g errgroup.Group
+	for _, image := range images {
+		image := RewriteImageRepository(image, imageRepository)
+		g.Go(func() error {
+			return cacheImage(image)
+		})
+	}
+	return g.Wait()
+}
+
+// cacheImage is a no-op if image is already in the cache and its digest
+// still checks out. Otherwise it pulls the image with the host's docker,
+// saves it to a tarball, and hands that off to storeImage.
+func cacheImage(image string) error {
+	if _, ok := cachedImagePath(image); ok {
+		return nil
+	}
+
+	if err := exec.Command("docker", "pull", image).Run(); err != nil {
+		return fmt.Errorf("pulling %s: %v", image, err)
+	}
+
+	tarball, err := ioutil.TempFile("", "minikube-image-")
+	if err != nil {
+		return err
+	}
+	tarball.Close()
+	defer os.Remove(tarball.Name())
+
+	if err := exec.Command("docker", "save", "-o", tarball.Name(), image).Run(); err != nil {
+		return fmt.Errorf("saving %s: %v", image, err)
+	}
+
+	return storeImage(image, tarball.Name())
+}
+
+// imagesForBootstrapper returns every image clusterBootstrapper needs to
+// stand up a cluster at k8sVersion.
+func imagesForBootstrapper(k8sVersion, clusterBootstrapper string) ([]string, error) {
+	switch clusterBootstrapper {
+	case "kubeadm":
+		return kubeadmImages(k8sVersion), nil
+	default:
+		return nil, fmt.Errorf("caching images for bootstrapper %q is not supported", clusterBootstrapper)
+	}
+}
+
+// kubeadmImages returns the core control-plane images a kubeadm-bootstrapped
+// cluster needs for version, which is the only part of the set that varies
+// with the Kubernetes version.
+func kubeadmImages(version string) []string {
+	v := strings.TrimPrefix(version, "v")
+	return []string{
+		"k8s.gcr.io/kube-apiserver:v" + v,
+		"k8s.gcr.io/kube-controller-manager:v" + v,
+		"k8s.gcr.io/kube-scheduler:v" + v,
+		"k8s.gcr.io/kube-proxy:v" + v,
+		"k8s.gcr.io/pause:3.1",
+		"k8s.gcr.io/etcd:3.2.24",
+		"k8s.gcr.io/coredns:1.2.2",
+	}
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Sync()
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net"
+)
+
+// ipAllocator hands out addresses from a CIDR on a first-come basis,
+// remembering which key (namespace/name) holds which address so that
+// restarts of the tunnel process reuse the same IP for the same Service.
+type ipAllocator struct {
+	network  *net.IPNet
+	byKey    map[string]net.IP
+	byIP     map[string]bool
+}
+
+func newIPAllocator(cidr string, restored map[string]string) (*ipAllocator, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing tunnel CIDR %q: %v", cidr, err)
+	}
+
+	a := &ipAllocator{
+		network: network,
+		byKey:   map[string]net.IP{},
+		byIP:    map[string]bool{},
+	}
+	for key, ip := range restored {
+		parsed := net.ParseIP(ip)
+		if parsed == nil || !network.Contains(parsed) {
+			continue
+		}
+		a.byKey[key] = parsed
+		a.byIP[parsed.String()] = true
+	}
+	return a, nil
+}
+
+// Allocate returns the IP previously assigned to key, if any, or the next
+// free address in the allocator's CIDR.
+func (a *ipAllocator) Allocate(key string) (net.IP, error) {
+	if ip, ok := a.byKey[key]; ok {
+		return ip, nil
+	}
+
+	for ip := nextIP(a.network.IP); a.network.Contains(ip); ip = nextIP(ip) {
+		if a.network.IP.Equal(ip) || isBroadcast(ip, a.network) {
+			continue
+		}
+		if a.byIP[ip.String()] {
+			continue
+		}
+		a.byKey[key] = ip
+		a.byIP[ip.String()] = true
+		return ip, nil
+	}
+	return nil, fmt.Errorf("no free addresses left in %s", a.network)
+}
+
+// Release frees the address held by key, allowing it to be reassigned.
+func (a *ipAllocator) Release(key string) {
+	ip, ok := a.byKey[key]
+	if !ok {
+		return
+	}
+	delete(a.byKey, key)
+	delete(a.byIP, ip.String())
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func isBroadcast(ip net.IP, network *net.IPNet) bool {
+	broadcast := make(net.IP, len(network.IP))
+	for i := range network.IP {
+		broadcast[i] = network.IP[i] | ^network.Mask[i]
+	}
+	return ip.Equal(broadcast)
+}
+
+// checkCIDROverlap guards against handing out tunnel IPs that could be
+// confused with addresses the cluster's Services or host-only network
+// already use.
+func checkCIDROverlap(tunnelCIDR string, others ...string) error {
+	_, tunnelNet, err := net.ParseCIDR(tunnelCIDR)
+	if err != nil {
+		return fmt.Errorf("parsing tunnel CIDR %q: %v", tunnelCIDR, err)
+	}
+
+	for _, other := range others {
+		if other == "" {
+			continue
+		}
+		_, otherNet, err := net.ParseCIDR(other)
+		if err != nil {
+			return fmt.Errorf("parsing CIDR %q: %v", other, err)
+		}
+		if tunnelNet.Contains(otherNet.IP) || otherNet.Contains(tunnelNet.IP) {
+			return fmt.Errorf("tunnel CIDR %s overlaps with %s", tunnelCIDR, other)
+		}
+	}
+	return nil
+}
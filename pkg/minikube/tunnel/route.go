@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"runtime"
+
+	"github.com/golang/glog"
+)
+
+// Route describes a host route that forwards traffic destined for an
+// ingress IP to the minikube VM's node IP.
+type Route struct {
+	IngressIP net.IP
+	NodeIP    net.IP
+}
+
+// installRoute adds a host route for ingressIP via nodeIP, using whatever
+// routing tool is native to the current OS.
+func installRoute(ingressIP, nodeIP net.IP) (*Route, error) {
+	route := &Route{IngressIP: ingressIP, NodeIP: nodeIP}
+	if err := runRouteCommand(addArgs(route)); err != nil {
+		return nil, fmt.Errorf("adding route %s -> %s: %v", ingressIP, nodeIP, err)
+	}
+	return route, nil
+}
+
+// removeRoute deletes a previously installed route. It tolerates the route
+// already being gone, since Cleanup may run after a reboot or a manual
+// `route del`.
+func removeRoute(route *Route) error {
+	if err := runRouteCommand(deleteArgs(route)); err != nil {
+		glog.Warningf("removing route for %s: %v (continuing)", route.IngressIP, err)
+	}
+	return nil
+}
+
+func runRouteCommand(args []string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, out)
+	}
+	return nil
+}
+
+// addArgs and deleteArgs build the OS-native `route` invocation for
+// installing/removing a host route to ingressIP via nodeIP.
+func addArgs(r *Route) []string {
+	switch runtime.GOOS {
+	case "windows":
+		return []string{"route", "ADD", r.IngressIP.String(), "MASK", "255.255.255.255", r.NodeIP.String()}
+	case "darwin":
+		return []string{"route", "-n", "add", r.IngressIP.String(), r.NodeIP.String()}
+	default: // linux and other unix-likes
+		return []string{"route", "add", "-host", r.IngressIP.String(), "gw", r.NodeIP.String()}
+	}
+}
+
+func deleteArgs(r *Route) []string {
+	switch runtime.GOOS {
+	case "windows":
+		return []string{"route", "DELETE", r.IngressIP.String()}
+	case "darwin":
+		return []string{"route", "-n", "delete", r.IngressIP.String(), r.NodeIP.String()}
+	default:
+		return []string{"route", "del", "-host", r.IngressIP.String(), "gw", r.NodeIP.String()}
+	}
+}
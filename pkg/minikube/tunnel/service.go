@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// pendingLoadBalancers returns every LoadBalancer Service across all
+// namespaces that has not yet been assigned an ingress IP.
+func pendingLoadBalancers(client kubernetes.Interface) ([]v1.Service, error) {
+	list, err := client.CoreV1().Services(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []v1.Service
+	for _, svc := range list.Items {
+		if svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+			continue
+		}
+		if len(svc.Status.LoadBalancer.Ingress) > 0 {
+			continue
+		}
+		pending = append(pending, svc)
+	}
+	return pending, nil
+}
+
+// patchIngress sets status.loadBalancer.ingress[0].ip on the named Service.
+func patchIngress(client kubernetes.Interface, namespace, name string, ip net.IP) error {
+	patch := fmt.Sprintf(`{"status":{"loadBalancer":{"ingress":[{"ip":%q}]}}}`, ip.String())
+	_, err := client.CoreV1().Services(namespace).Patch(name, types.MergePatchType, []byte(patch), "status")
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util/atomicfile"
+)
+
+// store persists the set of ingress IPs a tunnel has handed out, keyed by
+// "namespace/name", so that a tunnel process restarted after a crash
+// resumes with the same addresses instead of reallocating. `minikube stop`
+// clears it (see CleanupProfile).
+type store struct {
+	path      string
+	Allocated map[string]string `json:"allocated"`
+}
+
+// tunnelsDir returns $MINIKUBE_HOME/tunnels, creating it if necessary.
+func tunnelsDir() (string, error) {
+	dir := filepath.Join(constants.GetMinipath(), "tunnels")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func loadStore(profile string) (*store, error) {
+	dir, err := tunnelsDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, profile+".json")
+
+	st := &store{path: path, Allocated: map[string]string{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+	st.path = path
+	return st, nil
+}
+
+// Save writes the store back to disk. It is best-effort by convention: a
+// caller that fails to save keeps running, since the worst outcome is that
+// a future tunnel process reallocates an IP that was already in use.
+func (s *store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tunnel implements "minikube tunnel", which gives LoadBalancer
+// Services a real, routable external IP by running a small control loop on
+// the host: it watches the apiserver for Services of type LoadBalancer,
+// hands out addresses from a dedicated tunnel CIDR (kept apart from the
+// service and host-only CIDRs), patches the Service status
+// with the result, and installs a host route so that traffic to the
+// allocated IP is forwarded to the minikube VM.
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Manager owns the full lifecycle of a tunnel for a single profile: it
+// reconciles LoadBalancer Services against the IP allocator, keeps the
+// routing table in sync, and persists its state so that a restarted tunnel
+// process can pick up where the last one left off.
+type Manager struct {
+	profile   string
+	nodeIP    net.IP
+	client    kubernetes.Interface
+	allocator *ipAllocator
+	store     *store
+	reconcile time.Duration
+
+	// routes tracks the host route that was installed for each ingress IP,
+	// so Cleanup can remove exactly what was added.
+	routes map[string]*Route
+}
+
+// Config bundles everything the tunnel needs to know about the cluster it
+// is tunneling into.
+type Config struct {
+	Profile      string
+	NodeIP       net.IP
+	ServiceCIDR  string
+	HostOnlyCIDR string
+	// CIDR is the range tunnel allocates LoadBalancer ingress IPs from. It
+	// must not overlap ServiceCIDR or HostOnlyCIDR.
+	CIDR string
+}
+
+// NewManager constructs a Manager for the given cluster, restoring any
+// addresses that a previous tunnel run already handed out.
+func NewManager(client kubernetes.Interface, cfg Config) (*Manager, error) {
+	if err := checkCIDROverlap(cfg.CIDR, cfg.ServiceCIDR, cfg.HostOnlyCIDR); err != nil {
+		return nil, err
+	}
+
+	st, err := loadStore(cfg.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("loading tunnel state: %v", err)
+	}
+
+	alloc, err := newIPAllocator(cfg.CIDR, st.Allocated)
+	if err != nil {
+		return nil, fmt.Errorf("creating ip allocator: %v", err)
+	}
+
+	return &Manager{
+		profile:   cfg.Profile,
+		nodeIP:    cfg.NodeIP,
+		client:    client,
+		allocator: alloc,
+		store:     st,
+		reconcile: 5 * time.Second,
+		routes:    map[string]*Route{},
+	}, nil
+}
+
+// Run watches LoadBalancer Services until stopCh is closed, allocating an
+// ingress IP and a host route for each one it finds.
+func (m *Manager) Run(stopCh <-chan struct{}) error {
+	glog.Infof("Starting tunnel for profile %s against node %s", m.profile, m.nodeIP)
+	ticker := time.NewTicker(m.reconcile)
+	defer ticker.Stop()
+
+	for {
+		if err := m.reconcileOnce(); err != nil {
+			glog.Errorf("tunnel reconcile failed: %v", err)
+		}
+		select {
+		case <-stopCh:
+			return m.Cleanup()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) reconcileOnce() error {
+	services, err := pendingLoadBalancers(m.client)
+	if err != nil {
+		return fmt.Errorf("listing LoadBalancer services: %v", err)
+	}
+
+	for _, svc := range services {
+		key := svc.Namespace + "/" + svc.Name
+		if _, exists := m.routes[key]; exists {
+			continue
+		}
+
+		ip, err := m.allocator.Allocate(key)
+		if err != nil {
+			glog.Errorf("no IP available for %s: %v", key, err)
+			continue
+		}
+
+		route, err := installRoute(ip, m.nodeIP)
+		if err != nil {
+			m.allocator.Release(key)
+			return fmt.Errorf("installing route for %s: %v", key, err)
+		}
+		m.routes[key] = route
+
+		if err := patchIngress(m.client, svc.Namespace, svc.Name, ip); err != nil {
+			return fmt.Errorf("patching service status for %s: %v", key, err)
+		}
+
+		m.store.Allocated[key] = ip.String()
+		if err := m.store.Save(); err != nil {
+			glog.Errorf("saving tunnel state: %v", err)
+		}
+		glog.Infof("%s now routed to %s via %s", key, ip, m.nodeIP)
+	}
+	return nil
+}
+
+// Cleanup removes every host route this Manager installed. It is safe to
+// call more than once.
+func (m *Manager) Cleanup() error {
+	var firstErr error
+	for key, route := range m.routes {
+		if err := removeRoute(route); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("removing route for %s: %v", key, err)
+		}
+		delete(m.routes, key)
+	}
+	return firstErr
+}
+
+// CleanupProfile removes every host route recorded for profile's tunnel,
+// if any, and clears its persisted state. It is meant to be called from
+// `minikube stop` so that routes don't strand pointing at a VM that is no
+// longer running, if the user stops minikube from a different terminal
+// than the one running `minikube tunnel`.
+func CleanupProfile(profile string, nodeIP net.IP) error {
+	st, err := loadStore(profile)
+	if err != nil {
+		return fmt.Errorf("loading tunnel state: %v", err)
+	}
+
+	var firstErr error
+	for key, ipStr := range st.Allocated {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			continue
+		}
+		if err := removeRoute(&Route{IngressIP: ip, NodeIP: nodeIP}); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("removing route for %s: %v", key, err)
+		}
+	}
+
+	st.Allocated = map[string]string{}
+	if err := st.Save(); err != nil && firstErr == nil {
+		firstErr = fmt.Errorf("clearing tunnel state: %v", err)
+	}
+	return firstErr
+}
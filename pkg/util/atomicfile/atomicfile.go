@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package atomicfile writes files the way minikube's config, profile, and
+// kubeconfig writers all need to: write to a temp file next to the
+// destination, fsync it, replace the destination atomically, then fsync
+// the containing directory so the rename itself survives a crash. Callers
+// that used to hand-roll a write-temp-rename dance can use this instead.
+package atomicfile
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// noFsyncEnv lets test suites and tmpfs-backed CI opt out of the fsync
+// calls, which can dominate runtime on spinning disks or be outright
+// unsupported on some CI filesystems.
+const noFsyncEnv = "MINIKUBE_NO_FSYNC"
+
+// File is a temp file that becomes its destination only once Close
+// succeeds, so callers that stream large data (tarballs, ISOs, preloaded
+// image bundles) don't have to buffer it all in memory first just to get
+// an atomic write.
+type File struct {
+	*os.File
+	name    string // the path this will become on Close
+	tmpName string
+}
+
+// Create opens a temp file next to name for writing. The file at name is
+// left untouched until Close is called.
+func Create(name string) (*File, error) {
+	dir := filepath.Dir(name)
+	tmp, err := ioutil.TempFile(dir, filepath.Base(name)+".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %v", err)
+	}
+	return &File{File: tmp, name: name, tmpName: tmp.Name()}, nil
+}
+
+// Close syncs the temp file's contents, replaces name with it, and syncs
+// name's directory so the replacement is durable. The destination is left
+// untouched if any step before the replace fails, and the temp file is
+// removed on every error path instead of being left behind.
+func (f *File) Close() error {
+	if !fsyncDisabled() {
+		if err := f.File.Sync(); err != nil {
+			f.File.Close()
+			os.Remove(f.tmpName)
+			return fmt.Errorf("syncing temp file: %v", err)
+		}
+	}
+
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.tmpName)
+		return fmt.Errorf("closing temp file: %v", err)
+	}
+
+	// Best-effort: a missing destination (first write) or an OS that
+	// doesn't support chown is not a reason to fail the write.
+	_ = preserveMetadata(f.name, f.tmpName)
+
+	if err := replace(f.tmpName, f.name); err != nil {
+		os.Remove(f.tmpName)
+		return fmt.Errorf("replacing %s: %v", f.name, err)
+	}
+
+	if !fsyncDisabled() {
+		if err := syncDir(filepath.Dir(f.name)); err != nil {
+			return fmt.Errorf("syncing directory: %v", err)
+		}
+	}
+	return nil
+}
+
+// Abort discards the temp file without touching the destination. Use this
+// instead of Close when a write fails partway through.
+func (f *File) Abort() error {
+	f.File.Close()
+	return os.Remove(f.tmpName)
+}
+
+// CloseAndPurge is an alias for Abort, for callers that find the name
+// clearer at the call site than a bare Abort.
+func (f *File) CloseAndPurge() error {
+	return f.Abort()
+}
+
+// WriteFile atomically replaces filename's contents with data. If filename
+// already exists, its mode and (where the OS supports it) owner are
+// preserved on the replacement instead of falling back to perm.
+func WriteFile(filename string, data []byte, perm os.FileMode) error {
+	f, err := Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Abort()
+		return fmt.Errorf("writing temp file: %v", err)
+	}
+
+	if err := os.Chmod(f.tmpName, perm); err != nil {
+		f.Abort()
+		return fmt.Errorf("setting temp file mode: %v", err)
+	}
+
+	return f.Close()
+}
+
+func fsyncDisabled() bool {
+	return os.Getenv(noFsyncEnv) != ""
+}
+
+// replace atomically puts tmp in place of dest. On POSIX, rename(2)
+// already replaces dest atomically, so there's no separate remove step:
+// removing dest and then having the rename fail (disk full, EXDEV, a
+// permission change) would destroy the original with nothing to put back.
+// Windows' rename refuses to overwrite an existing file, so there the
+// remove+rename happens as two steps, done only now that tmp is fully
+// written and synced, so a failure while writing or syncing tmp still
+// leaves the original intact; a rename that fails after the remove leaves
+// dest missing.
+func replace(tmp, dest string) error {
+	if runtime.GOOS == "windows" {
+		if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	err := os.Rename(tmp, dest)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	// tmp and dest live on different filesystems (e.g. $TMPDIR is tmpfs
+	// while the destination's directory isn't), so the rename above can
+	// never succeed. Copy tmp's contents into a new temp file that lives
+	// next to dest instead, and rename that one in its place.
+	copied, err := copyToSibling(tmp, dest)
+	if err != nil {
+		return fmt.Errorf("copying across filesystems: %v", err)
+	}
+	defer os.Remove(copied)
+
+	if err := os.Rename(copied, dest); err != nil {
+		return err
+	}
+	return os.Remove(tmp)
+}
+
+// copyToSibling copies src into a new temp file in dest's directory,
+// syncing it before returning so the eventual rename has something
+// durable to point at.
+func copyToSibling(src, dest string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	out, err := ioutil.TempFile(filepath.Dir(dest), filepath.Base(dest)+".tmp")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", err
+	}
+
+	if !fsyncDisabled() {
+		if err := out.Sync(); err != nil {
+			out.Close()
+			os.Remove(out.Name())
+			return "", err
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package atomicfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWriteFileKeepsDestinationOnRenameFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A directory can never be the target of a rename from a regular
+	// file, so this reliably forces the replace step to fail without
+	// relying on OS-specific fault injection.
+	dest := filepath.Join(dir, "config.json")
+	if err := os.Mkdir(dest, 0700); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	if err := WriteFile(dest, []byte(`{"new":true}`), 0600); err == nil {
+		t.Fatal("expected WriteFile to fail when the destination is a directory")
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatalf("destination was lost after a failed write: %v", err)
+	}
+	if !info.IsDir() {
+		t.Fatal("destination should still be the original directory")
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if e.Name() != "config.json" {
+			t.Errorf("leftover temp file after failed write: %s", e.Name())
+		}
+	}
+}
+
+func TestWriteFilePreservesMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(dest, []byte(`{"old":true}`), 0640); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := WriteFile(dest, []byte(`{"new":true}`), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %o, want %o", info.Mode().Perm(), 0640)
+	}
+
+	got, err := ioutil.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != `{"new":true}` {
+		t.Errorf("contents = %q, want %q", got, `{"new":true}`)
+	}
+}
+
+func TestWriteFileNewFileUsesPerm(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "config.json")
+	if err := WriteFile(dest, []byte(`{"new":true}`), 0640); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %o, want %o", info.Mode().Perm(), 0640)
+	}
+}
+
+func TestCreateStreamsBeforeTouchingDestination(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "bundle.tar")
+	if err := ioutil.WriteFile(dest, []byte("original"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := Create(dest)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := f.Write([]byte("partial write before a failure")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// Simulate a mid-stream failure: the caller aborts instead of closing.
+	if err := f.Abort(); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+
+	got, err := ioutil.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "original" {
+		t.Errorf("destination changed after Abort: got %q, want %q", got, "original")
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if e.Name() != "bundle.tar" {
+			t.Errorf("leftover temp file after Abort: %s", e.Name())
+		}
+	}
+}
+
+func TestCreateCloseStreamsIntoPlace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "bundle.tar")
+	f, err := Create(dest)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := f.Write([]byte("streamed contents")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := ioutil.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "streamed contents" {
+		t.Errorf("contents = %q, want %q", got, "streamed contents")
+	}
+}
+
+func TestIsCrossDevice(t *testing.T) {
+	exdev := &os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.EXDEV}
+	if !isCrossDevice(exdev) {
+		t.Error("expected an EXDEV LinkError to be detected as cross-device")
+	}
+
+	other := &os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.ENOENT}
+	if isCrossDevice(other) {
+		t.Error("a non-EXDEV LinkError should not be treated as cross-device")
+	}
+
+	if isCrossDevice(os.ErrNotExist) {
+		t.Error("an unrelated error should not be treated as cross-device")
+	}
+}
+
+func TestCopyToSiblingCopiesContents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	if err := ioutil.WriteFile(src, []byte("cross-device payload"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	dest := filepath.Join(dir, "dest")
+
+	copied, err := copyToSibling(src, dest)
+	if err != nil {
+		t.Fatalf("copyToSibling: %v", err)
+	}
+	defer os.Remove(copied)
+
+	if filepath.Dir(copied) != dir {
+		t.Errorf("copy landed in %s, want sibling of %s", filepath.Dir(copied), dest)
+	}
+
+	got, err := ioutil.ReadFile(copied)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "cross-device payload" {
+		t.Errorf("contents = %q, want %q", got, "cross-device payload")
+	}
+}
+
+func TestWriteFileRespectsNoFsyncEnv(t *testing.T) {
+	os.Setenv(noFsyncEnv, "1")
+	defer os.Unsetenv(noFsyncEnv)
+
+	dir, err := ioutil.TempDir("", "atomicfile-test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "config.json")
+	if err := WriteFile(dest, []byte(`{"new":true}`), 0600); err != nil {
+		t.Fatalf("WriteFile with %s set: %v", noFsyncEnv, err)
+	}
+}
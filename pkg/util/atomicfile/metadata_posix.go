@@ -0,0 +1,67 @@
+// +build !windows
+
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package atomicfile
+
+import (
+	"os"
+	"syscall"
+)
+
+// preserveMetadata copies dst's mode, uid, and gid onto src, so an atomic
+// replace doesn't silently tighten an existing file's permissions.
+func preserveMetadata(dst, src string) error {
+	info, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(src, info.Mode()); err != nil {
+		return err
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if err := os.Chown(src, int(stat.Uid), int(stat.Gid)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDir fsyncs a directory so that a rename performed inside it is
+// durable across a crash, not just visible to processes that haven't
+// rebooted yet.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// isCrossDevice reports whether err is the rename(2) failure you get when
+// the source and destination are on different filesystems.
+func isCrossDevice(err error) bool {
+	linkErr, ok := err.(*os.LinkError)
+	if !ok {
+		return false
+	}
+	errno, ok := linkErr.Err.(syscall.Errno)
+	return ok && errno == syscall.EXDEV
+}
@@ -0,0 +1,55 @@
+// +build windows
+
+/*
+Copyright 2018 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package atomicfile
+
+import (
+	"os"
+	"syscall"
+)
+
+// preserveMetadata copies dst's mode onto src. Windows has no uid/gid to
+// carry over.
+func preserveMetadata(dst, src string) error {
+	info, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(src, info.Mode())
+}
+
+// syncDir is a no-op on Windows: directory handles don't support Sync,
+// and NTFS's own rename journaling makes a directory fsync unnecessary.
+func syncDir(dir string) error {
+	return nil
+}
+
+// isCrossDevice reports whether err is the rename failure you get when the
+// source and destination are on different volumes.
+func isCrossDevice(err error) bool {
+	linkErr, ok := err.(*os.LinkError)
+	if !ok {
+		return false
+	}
+	errno, ok := linkErr.Err.(syscall.Errno)
+	return ok && errno == errorNotSameDevice
+}
+
+// errorNotSameDevice is Windows' ERROR_NOT_SAME_DEVICE, which the syscall
+// package does not define.
+const errorNotSameDevice syscall.Errno = 17